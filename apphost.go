@@ -1,7 +1,9 @@
 package astraljs
 
 import (
+	"context"
 	_ "embed"
+	"encoding/base64"
 	"errors"
 	"fmt"
 	"github.com/cryptopunkscc/astrald/auth/id"
@@ -14,18 +16,24 @@ import (
 )
 
 const (
-	Log             = "log"
-	Sleep           = "sleep"
-	ServiceRegister = "astral_service_register"
-	ServiceClose    = "astral_service_close"
-	ConnAccept      = "astral_conn_accept"
-	ConnClose       = "astral_conn_close"
-	ConnWrite       = "astral_conn_write"
-	ConnRead        = "astral_conn_read"
-	Query           = "astral_query"
-	QueryName       = "astral_query_name"
-	GetNodeInfo     = "astral_node_info"
-	Resolve         = "astral_resolve"
+	Log              = "log"
+	Sleep            = "sleep"
+	ServiceRegister  = "astral_service_register"
+	ServiceClose     = "astral_service_close"
+	ConnAccept       = "astral_conn_accept"
+	ConnClose        = "astral_conn_close"
+	ConnWrite        = "astral_conn_write"
+	ConnRead         = "astral_conn_read"
+	ConnWriteBytes   = "astral_conn_write_bytes"
+	ConnReadBytes    = "astral_conn_read_bytes"
+	ConnWriteFrame   = "astral_conn_write_frame"
+	ConnReadFrame    = "astral_conn_read_frame"
+	Query            = "astral_query"
+	QueryName        = "astral_query_name"
+	GetNodeInfo      = "astral_node_info"
+	Resolve          = "astral_resolve"
+	CancelService    = "astral_service_cancel"
+	CancelConnection = "astral_conn_cancel"
 )
 
 //go:embed apphost.js
@@ -38,17 +46,24 @@ func AppHostJsClient() string {
 type AppHostFlatAdapter struct {
 	closed bool
 
-	listeners      map[string]*astral.Listener
-	listenersMutex sync.RWMutex
+	listeners       map[string]*astral.Listener
+	listenerCancels map[string]context.CancelFunc
+	listenersMutex  sync.RWMutex
 
 	connections      map[string]io.ReadWriteCloser
+	connCancels      map[string]context.CancelFunc
 	connectionsMutex sync.RWMutex
+
+	maxFrameSize int
 }
 
 func NewAppHostFlatAdapter() *AppHostFlatAdapter {
 	return &AppHostFlatAdapter{
-		listeners:   map[string]*astral.Listener{},
-		connections: map[string]io.ReadWriteCloser{},
+		listeners:       map[string]*astral.Listener{},
+		listenerCancels: map[string]context.CancelFunc{},
+		connections:     map[string]io.ReadWriteCloser{},
+		connCancels:     map[string]context.CancelFunc{},
+		maxFrameSize:    defaultMaxFrameSize,
 	}
 }
 
@@ -57,6 +72,12 @@ func CloseAppHostFlatAdapter(api *AppHostFlatAdapter) {
 	api.connectionsMutex.Lock()
 	defer api.listenersMutex.Unlock()
 	defer api.connectionsMutex.Unlock()
+	for _, cancel := range api.listenerCancels {
+		cancel()
+	}
+	for _, cancel := range api.connCancels {
+		cancel()
+	}
 	for _, closer := range api.listeners {
 		_ = closer.Close()
 	}
@@ -64,7 +85,9 @@ func CloseAppHostFlatAdapter(api *AppHostFlatAdapter) {
 		_ = closer.Close()
 	}
 	api.connections = nil
+	api.connCancels = nil
 	api.listeners = nil
+	api.listenerCancels = nil
 	api.closed = true
 	log.Println("[AppHostFlatAdapter] closed")
 }
@@ -92,6 +115,78 @@ func (api *AppHostFlatAdapter) setListener(service string, listener *astral.List
 	}
 }
 
+func (api *AppHostFlatAdapter) getListenerCancel(service string) (cancel context.CancelFunc, ok bool) {
+	api.listenersMutex.RLock()
+	defer api.listenersMutex.RUnlock()
+	if api.closed {
+		return
+	}
+	cancel, ok = api.listenerCancels[service]
+	return
+}
+
+func (api *AppHostFlatAdapter) setListenerCancel(service string, cancel context.CancelFunc) {
+	api.listenersMutex.Lock()
+	defer api.listenersMutex.Unlock()
+	if api.closed {
+		return
+	}
+	if cancel != nil {
+		api.listenerCancels[service] = cancel
+	} else {
+		delete(api.listenerCancels, service)
+	}
+}
+
+// trackListener creates a cancel scope for a newly registered listener and
+// stores its cancel func in listenerCancels: when that cancel func runs (via CancelService or a *Ctx caller's ctx
+// cancellation), the listener is closed so any goroutine blocked in
+// listener.Accept() unblocks instead of leaking.
+func (api *AppHostFlatAdapter) trackListener(service string, listener *astral.Listener) {
+	ctx, cancel := context.WithCancel(context.Background())
+	api.setListenerCancel(service, cancel)
+	go func() {
+		<-ctx.Done()
+		_ = listener.Close()
+	}()
+}
+
+func (api *AppHostFlatAdapter) getConnCancel(connectionId string) (cancel context.CancelFunc, ok bool) {
+	api.connectionsMutex.RLock()
+	defer api.connectionsMutex.RUnlock()
+	if api.closed {
+		return
+	}
+	cancel, ok = api.connCancels[connectionId]
+	return
+}
+
+func (api *AppHostFlatAdapter) setConnCancel(connectionId string, cancel context.CancelFunc) {
+	api.connectionsMutex.Lock()
+	defer api.connectionsMutex.Unlock()
+	if api.closed {
+		return
+	}
+	if cancel != nil {
+		api.connCancels[connectionId] = cancel
+	} else {
+		delete(api.connCancels, connectionId)
+	}
+}
+
+// trackConnection creates a cancel scope for a newly stored connection and
+// stores its cancel func in connCancels: when that cancel func runs (via CancelConnection or a *Ctx caller's ctx
+// cancellation), the connection is closed so any goroutine blocked reading or
+// writing it unblocks instead of leaking.
+func (api *AppHostFlatAdapter) trackConnection(connectionId string, conn io.ReadWriteCloser) {
+	ctx, cancel := context.WithCancel(context.Background())
+	api.setConnCancel(connectionId, cancel)
+	go func() {
+		<-ctx.Done()
+		_ = conn.Close()
+	}()
+}
+
 func (api *AppHostFlatAdapter) getConnection(connectionId string) (rw io.ReadWriteCloser, ok bool) {
 	api.connectionsMutex.RLock()
 	defer api.connectionsMutex.RUnlock()
@@ -102,6 +197,29 @@ func (api *AppHostFlatAdapter) getConnection(connectionId string) (rw io.ReadWri
 	return
 }
 
+func (api *AppHostFlatAdapter) getDeadlineConn(connectionId string) (conn *deadlineConn, err error) {
+	rw, ok := api.getConnection(connectionId)
+	if !ok {
+		err = errors.New("[getDeadlineConn] not found connection with id: " + connectionId)
+		return
+	}
+	conn, ok = rw.(*deadlineConn)
+	if !ok {
+		err = errors.New("[getDeadlineConn] connection does not support deadlines: " + connectionId)
+		return
+	}
+	return
+}
+
+// unixMillisToDeadline converts a millisecond Unix timestamp into the
+// time.Time shape deadlineConn expects, treating 0 as "no deadline".
+func unixMillisToDeadline(unixMillis int64) time.Time {
+	if unixMillis == 0 {
+		return time.Time{}
+	}
+	return time.UnixMilli(unixMillis)
+}
+
 func (api *AppHostFlatAdapter) setConnection(connectionId string, connection io.ReadWriteCloser) {
 	api.connectionsMutex.Lock()
 	defer api.connectionsMutex.Unlock()
@@ -133,6 +251,7 @@ func (api *AppHostFlatAdapter) ServiceRegister(service string) (err error) {
 		return
 	}
 	api.setListener(service, listener)
+	api.trackListener(service, listener)
 	return
 }
 
@@ -146,6 +265,23 @@ func (api *AppHostFlatAdapter) ServiceClose(service string) (err error) {
 	if err != nil {
 		api.setListener(service, nil)
 	}
+	if cancel, ok := api.getListenerCancel(service); ok {
+		cancel()
+		api.setListenerCancel(service, nil)
+	}
+	return
+}
+
+// CancelService unblocks any goroutine currently parked in ConnAccept (or
+// ConnAcceptCtx) for service by closing its listener, without waiting for a
+// JS-side AbortSignal to propagate through a context.
+func (api *AppHostFlatAdapter) CancelService(service string) (err error) {
+	cancel, ok := api.getListenerCancel(service)
+	if !ok {
+		err = errors.New("[CancelService] not listening on port: " + service)
+		return
+	}
+	cancel()
 	return
 }
 
@@ -160,7 +296,9 @@ func (api *AppHostFlatAdapter) ConnAccept(service string) (id string, err error)
 		return
 	}
 	id = uuid.New().String()
-	api.setConnection(id, conn)
+	dConn := newDeadlineConn(conn)
+	api.setConnection(id, dConn)
+	api.trackConnection(id, dConn)
 	return
 }
 
@@ -174,43 +312,106 @@ func (api *AppHostFlatAdapter) ConnClose(id string) (err error) {
 	if err == nil {
 		api.setConnection(id, nil)
 	}
+	if cancel, ok := api.getConnCancel(id); ok {
+		cancel()
+		api.setConnCancel(id, nil)
+	}
 	return
 }
 
-func (api *AppHostFlatAdapter) ConnWrite(id string, data string) (err error) {
-	conn, ok := api.getConnection(id)
+// CancelConnection unblocks any goroutine currently parked in ConnRead,
+// ConnWrite, or their *Ctx variants for id by closing the connection.
+func (api *AppHostFlatAdapter) CancelConnection(id string) (err error) {
+	cancel, ok := api.getConnCancel(id)
 	if !ok {
-		err = errors.New("[ConnWrite] not found connection with id: " + id)
+		err = errors.New("[CancelConnection] not found connection with id: " + id)
 		return
 	}
-	_, err = conn.Write([]byte(data))
+	cancel()
 	return
 }
 
+// ConnWrite writes data as-is through Go's string type. It delegates to
+// ConnWriteBytes internally; prefer ConnWriteBytes directly for payloads
+// that aren't valid UTF-8, since round-tripping them through string can
+// corrupt them.
+func (api *AppHostFlatAdapter) ConnWrite(id string, data string) (err error) {
+	return api.ConnWriteBytes(id, base64.StdEncoding.EncodeToString([]byte(data)))
+}
+
+// ConnRead reads until a short read, accumulating into a Go string. It
+// delegates to ConnReadBytes internally; prefer ConnReadBytes directly for
+// binary payloads, since routing them through string can corrupt them.
 func (api *AppHostFlatAdapter) ConnRead(id string) (data string, err error) {
-	conn, ok := api.getConnection(id)
-	if !ok {
-		err = errors.New("[ConnRead] not found connection with id: " + id)
-		return
-	}
-	buf := make([]byte, 4096)
+	const chunk = 4096
 	arr := make([]byte, 0)
-	n := 0
 	defer func() {
 		data = string(arr)
 	}()
 	for {
-		n, err = conn.Read(buf)
+		var b64 string
+		var decoded []byte
+		b64, err = api.ConnReadBytes(id, chunk)
+		if err != nil {
+			return
+		}
+		decoded, err = base64.StdEncoding.DecodeString(b64)
 		if err != nil {
 			return
 		}
-		arr = append(arr, buf[0:n]...)
-		if n < len(buf) {
+		arr = append(arr, decoded...)
+		if len(decoded) < chunk {
 			return
 		}
 	}
 }
 
+// ConnReadN reads at most max bytes from the connection, returning whatever
+// arrives before timeoutMs elapses instead of looping until a short read -
+// unlike ConnRead, a slow or silent peer does not force the caller to wait
+// for a full buffer.
+func (api *AppHostFlatAdapter) ConnReadN(id string, max int, timeoutMs int64) (data string, err error) {
+	conn, err := api.getDeadlineConn(id)
+	if err != nil {
+		return
+	}
+	if timeoutMs > 0 {
+		conn.SetReadDeadline(time.Now().Add(time.Duration(timeoutMs) * time.Millisecond))
+		defer conn.SetReadDeadline(time.Time{})
+	}
+	buf := make([]byte, max)
+	n, err := conn.Read(buf)
+	data = string(buf[:n])
+	return
+}
+
+func (api *AppHostFlatAdapter) ConnSetReadDeadline(id string, unixMillis int64) (err error) {
+	conn, err := api.getDeadlineConn(id)
+	if err != nil {
+		return
+	}
+	conn.SetReadDeadline(unixMillisToDeadline(unixMillis))
+	return
+}
+
+func (api *AppHostFlatAdapter) ConnSetWriteDeadline(id string, unixMillis int64) (err error) {
+	conn, err := api.getDeadlineConn(id)
+	if err != nil {
+		return
+	}
+	conn.SetWriteDeadline(unixMillisToDeadline(unixMillis))
+	return
+}
+
+func (api *AppHostFlatAdapter) ConnSetDeadline(id string, unixMillis int64) (err error) {
+	conn, err := api.getDeadlineConn(id)
+	if err != nil {
+		return
+	}
+	conn.SetDeadline(unixMillisToDeadline(unixMillis))
+	return
+}
+
 func (api *AppHostFlatAdapter) Query(identity string, query string) (connId string, err error) {
 	nid := id.Identity{}
 	if len(identity) > 0 {
@@ -224,7 +425,9 @@ func (api *AppHostFlatAdapter) Query(identity string, query string) (connId stri
 		return
 	}
 	connId = uuid.New().String()
-	api.setConnection(connId, conn)
+	dConn := newDeadlineConn(conn)
+	api.setConnection(connId, dConn)
+	api.trackConnection(connId, dConn)
 	return
 }
 
@@ -234,7 +437,9 @@ func (api *AppHostFlatAdapter) QueryName(name string, query string) (connId stri
 		return
 	}
 	connId = uuid.New().String()
-	api.setConnection(connId, conn)
+	dConn := newDeadlineConn(conn)
+	api.setConnection(connId, dConn)
+	api.trackConnection(connId, dConn)
 	return
 }
 
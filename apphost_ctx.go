@@ -0,0 +1,171 @@
+package astraljs
+
+import "context"
+
+// The *Ctx methods mirror their plain AppHostFlatAdapter counterparts but
+// honor ctx cancellation: each starts the blocking astral call in a
+// goroutine and selects on its result against <-ctx.Done(). In ConnAcceptCtx,
+// ConnReadCtx and ConnWriteCtx, cancellation unblocks the goroutine the same
+// way CancelService/CancelConnection do - by closing the listener or
+// connection backing it. ServiceRegisterCtx, ResolveCtx and NodeInfoCtx have
+// nothing to close: on cancellation they return ctx.Err() and the underlying
+// call keeps running in its goroutine until it finishes on its own.
+
+func (api *AppHostFlatAdapter) ServiceRegisterCtx(ctx context.Context, service string) (err error) {
+	resCh := make(chan error, 1)
+	go func() {
+		resCh <- api.ServiceRegister(service)
+	}()
+	select {
+	case err = <-resCh:
+		return
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (api *AppHostFlatAdapter) ConnAcceptCtx(ctx context.Context, service string) (connId string, err error) {
+	type result struct {
+		id  string
+		err error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		id, err := api.ConnAccept(service)
+		resCh <- result{id, err}
+	}()
+	select {
+	case res := <-resCh:
+		return res.id, res.err
+	case <-ctx.Done():
+		if cancel, ok := api.getListenerCancel(service); ok {
+			cancel()
+		}
+		return "", ctx.Err()
+	}
+}
+
+// QueryCtx and QueryNameCtx cannot interrupt astral.Query/astral.QueryName
+// mid-flight - the vendored astral client has no ctx-aware variant to hand
+// the blocking call to. Instead, on cancellation they detach a cleanup
+// goroutine that closes whatever connection eventually comes back, so a
+// torn-down JS request doesn't leave an orphaned connection alive.
+
+func (api *AppHostFlatAdapter) QueryCtx(ctx context.Context, identity string, query string) (connId string, err error) {
+	type result struct {
+		connId string
+		err    error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		connId, err := api.Query(identity, query)
+		resCh <- result{connId, err}
+	}()
+	select {
+	case res := <-resCh:
+		return res.connId, res.err
+	case <-ctx.Done():
+		go func() {
+			if res := <-resCh; res.err == nil {
+				_ = api.ConnClose(res.connId)
+			}
+		}()
+		return "", ctx.Err()
+	}
+}
+
+func (api *AppHostFlatAdapter) QueryNameCtx(ctx context.Context, name string, query string) (connId string, err error) {
+	type result struct {
+		connId string
+		err    error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		connId, err := api.QueryName(name, query)
+		resCh <- result{connId, err}
+	}()
+	select {
+	case res := <-resCh:
+		return res.connId, res.err
+	case <-ctx.Done():
+		go func() {
+			if res := <-resCh; res.err == nil {
+				_ = api.ConnClose(res.connId)
+			}
+		}()
+		return "", ctx.Err()
+	}
+}
+
+func (api *AppHostFlatAdapter) ResolveCtx(ctx context.Context, name string) (id string, err error) {
+	type result struct {
+		id  string
+		err error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		id, err := api.Resolve(name)
+		resCh <- result{id, err}
+	}()
+	select {
+	case res := <-resCh:
+		return res.id, res.err
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+func (api *AppHostFlatAdapter) NodeInfoCtx(ctx context.Context, identity string) (info NodeInfo, err error) {
+	type result struct {
+		info NodeInfo
+		err  error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		info, err := api.NodeInfo(identity)
+		resCh <- result{info, err}
+	}()
+	select {
+	case res := <-resCh:
+		return res.info, res.err
+	case <-ctx.Done():
+		return NodeInfo{}, ctx.Err()
+	}
+}
+
+func (api *AppHostFlatAdapter) ConnReadCtx(ctx context.Context, id string) (data string, err error) {
+	type result struct {
+		data string
+		err  error
+	}
+	resCh := make(chan result, 1)
+	go func() {
+		data, err := api.ConnRead(id)
+		resCh <- result{data, err}
+	}()
+	select {
+	case res := <-resCh:
+		return res.data, res.err
+	case <-ctx.Done():
+		if cancel, ok := api.getConnCancel(id); ok {
+			cancel()
+		}
+		return "", ctx.Err()
+	}
+}
+
+func (api *AppHostFlatAdapter) ConnWriteCtx(ctx context.Context, id string, data string) (err error) {
+	resCh := make(chan error, 1)
+	go func() {
+		resCh <- api.ConnWrite(id, data)
+	}()
+	select {
+	case err = <-resCh:
+		return
+	case <-ctx.Done():
+		if cancel, ok := api.getConnCancel(id); ok {
+			cancel()
+		}
+		return ctx.Err()
+	}
+}
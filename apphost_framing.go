@@ -0,0 +1,117 @@
+package astraljs
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// defaultMaxFrameSize bounds ConnReadFrame so a peer sending a bogus or
+// hostile length prefix can't make the adapter allocate an unbounded buffer.
+const defaultMaxFrameSize = 16 * 1024 * 1024 // 16 MiB
+
+// frameHeaderSize is the width of the length prefix ConnWriteFrame/
+// ConnReadFrame use to delimit messages on top of the raw byte stream.
+const frameHeaderSize = 4
+
+var ErrFrameTooLarge = errors.New("[ConnReadFrame] frame exceeds maximum size")
+
+func (api *AppHostFlatAdapter) getMaxFrameSize() int {
+	api.connectionsMutex.RLock()
+	defer api.connectionsMutex.RUnlock()
+	return api.maxFrameSize
+}
+
+// SetMaxFrameSize overrides the default 16 MiB cap ConnReadFrame enforces on
+// incoming frame lengths.
+func (api *AppHostFlatAdapter) SetMaxFrameSize(max int) {
+	api.connectionsMutex.Lock()
+	defer api.connectionsMutex.Unlock()
+	api.maxFrameSize = max
+}
+
+// ConnWriteBytes decodes b64 (base64 from the JS bridge) and writes the raw
+// bytes without forcing them through a Go string - unlike ConnWrite, non-UTF-8 payloads survive the
+// round trip intact.
+func (api *AppHostFlatAdapter) ConnWriteBytes(id string, b64 string) (err error) {
+	conn, ok := api.getConnection(id)
+	if !ok {
+		err = errors.New("[ConnWriteBytes] not found connection with id: " + id)
+		return
+	}
+	data, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return
+	}
+	_, err = conn.Write(data)
+	return
+}
+
+// ConnReadBytes reads up to max bytes and returns them base64-encoded,
+// leaving binary payloads untouched instead of mangling them through a Go
+// string as ConnRead does.
+func (api *AppHostFlatAdapter) ConnReadBytes(id string, max int) (b64 string, err error) {
+	conn, ok := api.getConnection(id)
+	if !ok {
+		err = errors.New("[ConnReadBytes] not found connection with id: " + id)
+		return
+	}
+	buf := make([]byte, max)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return
+	}
+	b64 = base64.StdEncoding.EncodeToString(buf[:n])
+	return
+}
+
+// ConnWriteFrame writes payload (base64-encoded) prefixed with its length as
+// a 4-byte big-endian header, so the reader can pull exactly one message off
+// the stream without its own framing convention.
+func (api *AppHostFlatAdapter) ConnWriteFrame(id string, b64 string) (err error) {
+	conn, ok := api.getConnection(id)
+	if !ok {
+		err = errors.New("[ConnWriteFrame] not found connection with id: " + id)
+		return
+	}
+	payload, err := base64.StdEncoding.DecodeString(b64)
+	if err != nil {
+		return
+	}
+	header := make([]byte, frameHeaderSize)
+	binary.BigEndian.PutUint32(header, uint32(len(payload)))
+	if _, err = conn.Write(header); err != nil {
+		return
+	}
+	_, err = conn.Write(payload)
+	return
+}
+
+// ConnReadFrame reads one length-prefixed message written by ConnWriteFrame
+// and returns its payload base64-encoded. It reads the 4-byte header first,
+// rejects lengths over the configured cap (see SetMaxFrameSize) with
+// ErrFrameTooLarge, then reads exactly that many bytes - unlike ConnRead,
+// it stops on a logical message boundary rather than a short read.
+func (api *AppHostFlatAdapter) ConnReadFrame(id string) (b64 string, err error) {
+	conn, ok := api.getConnection(id)
+	if !ok {
+		err = errors.New("[ConnReadFrame] not found connection with id: " + id)
+		return
+	}
+	header := make([]byte, frameHeaderSize)
+	if _, err = io.ReadFull(conn, header); err != nil {
+		return
+	}
+	size := binary.BigEndian.Uint32(header)
+	if max := api.getMaxFrameSize(); size > uint32(max) {
+		err = ErrFrameTooLarge
+		return
+	}
+	payload := make([]byte, size)
+	if _, err = io.ReadFull(conn, payload); err != nil {
+		return
+	}
+	b64 = base64.StdEncoding.EncodeToString(payload)
+	return
+}
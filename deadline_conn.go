@@ -0,0 +1,202 @@
+package astraljs
+
+import (
+	"io"
+	"sync"
+	"time"
+)
+
+// deadlineErr is returned by deadlineConn when a read or write is aborted
+// because its deadline elapsed. It satisfies the net.Error Timeout() contract
+// even though astral connections are plain io.ReadWriteClosers with no
+// native deadline support of their own.
+type deadlineErr struct{ op string }
+
+func (e *deadlineErr) Error() string   { return "astraljs: " + e.op + " deadline exceeded" }
+func (e *deadlineErr) Timeout() bool   { return true }
+func (e *deadlineErr) Temporary() bool { return true }
+
+type ioResult struct {
+	n   int
+	err error
+}
+
+type pendingIO struct {
+	buf   []byte
+	resCh chan ioResult
+}
+
+// deadlineHalf tracks the cancellation state for one direction (read or
+// write) of a deadlineConn, mirroring the pattern gonet uses to bolt
+// deadlines onto netstack connections that otherwise have none.
+type deadlineHalf struct {
+	cancelCh chan struct{}
+	timer    *time.Timer
+	pending  *pendingIO
+
+	// leftover holds bytes an abandoned pending read actually received from
+	// the conn but that didn't fit in the caller's buffer at the time; they
+	// are served to the front of the next Read before any new underlying
+	// read starts, so a timed-out call never drops buffered stream data.
+	// leftoverErr is the error that came with them, delivered once drained.
+	leftover    []byte
+	leftoverErr error
+}
+
+func newDeadlineHalf() *deadlineHalf {
+	return &deadlineHalf{cancelCh: make(chan struct{})}
+}
+
+// set installs t as the deadline for this half, closing cancelCh once it
+// elapses (or immediately, if t is already in the past). A zero t disables
+// the deadline.
+func (h *deadlineHalf) set(t time.Time) {
+	if h.timer != nil {
+		if !h.timer.Stop() {
+			h.cancelCh = make(chan struct{})
+		}
+		h.timer = nil
+	} else {
+		select {
+		case <-h.cancelCh:
+			h.cancelCh = make(chan struct{})
+		default:
+		}
+	}
+
+	if t.IsZero() {
+		return
+	}
+
+	now := time.Now()
+	if !t.After(now) {
+		close(h.cancelCh)
+		return
+	}
+
+	ch := h.cancelCh
+	h.timer = time.AfterFunc(t.Sub(now), func() { close(ch) })
+}
+
+// deadlineConn wraps an astral connection with read/write deadlines that the
+// underlying io.ReadWriteCloser does not support natively. Each direction's
+// blocking call runs in its own goroutine so a timed-out operation can be
+// abandoned without leaking: its result is picked up by the next call on the
+// same half, or discarded when the connection is closed.
+type deadlineConn struct {
+	io.ReadWriteCloser
+
+	mu    sync.Mutex
+	read  *deadlineHalf
+	write *deadlineHalf
+}
+
+func newDeadlineConn(conn io.ReadWriteCloser) *deadlineConn {
+	return &deadlineConn{
+		ReadWriteCloser: conn,
+		read:            newDeadlineHalf(),
+		write:           newDeadlineHalf(),
+	}
+}
+
+func (c *deadlineConn) SetReadDeadline(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.read.set(t)
+}
+
+func (c *deadlineConn) SetWriteDeadline(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.write.set(t)
+}
+
+func (c *deadlineConn) SetDeadline(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.read.set(t)
+	c.write.set(t)
+}
+
+func (c *deadlineConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	if len(c.read.leftover) > 0 {
+		n := copy(b, c.read.leftover)
+		c.read.leftover = c.read.leftover[n:]
+		var err error
+		if len(c.read.leftover) == 0 {
+			err = c.read.leftoverErr
+			c.read.leftoverErr = nil
+		}
+		c.mu.Unlock()
+		return n, err
+	}
+	if c.read.pending == nil {
+		buf := make([]byte, len(b))
+		resCh := make(chan ioResult, 1)
+		c.read.pending = &pendingIO{buf: buf, resCh: resCh}
+		go func(r io.Reader, buf []byte, resCh chan ioResult) {
+			n, err := r.Read(buf)
+			resCh <- ioResult{n, err}
+		}(c.ReadWriteCloser, buf, resCh)
+	}
+	pending := c.read.pending
+	cancelCh := c.read.cancelCh
+	c.mu.Unlock()
+
+	select {
+	case res := <-pending.resCh:
+		c.mu.Lock()
+		c.read.pending = nil
+		n := copy(b, pending.buf[:res.n])
+		var err error
+		if n < res.n {
+			// The buffer that started this read was sized for that caller,
+			// not necessarily for whoever picks up the result - stash what
+			// didn't fit instead of dropping it.
+			c.read.leftover = append([]byte(nil), pending.buf[n:res.n]...)
+			c.read.leftoverErr = res.err
+		} else {
+			err = res.err
+		}
+		c.mu.Unlock()
+		return n, err
+	case <-cancelCh:
+		return 0, &deadlineErr{op: "read"}
+	}
+}
+
+func (c *deadlineConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	if c.write.pending == nil {
+		buf := make([]byte, len(b))
+		copy(buf, b)
+		resCh := make(chan ioResult, 1)
+		c.write.pending = &pendingIO{buf: buf, resCh: resCh}
+		go func(w io.Writer, buf []byte, resCh chan ioResult) {
+			n, err := w.Write(buf)
+			resCh <- ioResult{n, err}
+		}(c.ReadWriteCloser, buf, resCh)
+	}
+	pending := c.write.pending
+	cancelCh := c.write.cancelCh
+	c.mu.Unlock()
+
+	select {
+	case res := <-pending.resCh:
+		c.mu.Lock()
+		c.write.pending = nil
+		c.mu.Unlock()
+		return res.n, res.err
+	case <-cancelCh:
+		return 0, &deadlineErr{op: "write"}
+	}
+}
+
+func (c *deadlineConn) Close() error {
+	c.mu.Lock()
+	c.read.set(time.Unix(0, 1))
+	c.write.set(time.Unix(0, 1))
+	c.mu.Unlock()
+	return c.ReadWriteCloser.Close()
+}